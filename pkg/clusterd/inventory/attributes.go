@@ -0,0 +1,98 @@
+package inventory
+
+import (
+	"fmt"
+	"path"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/quantum/castle/pkg/util"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// AttrsKey is the subkey under a node's config where free-form placement attributes live.
+const AttrsKey = "attrs"
+
+// Well-known attribute keys. Callers are free to set arbitrary keys, but these are the ones
+// PlacementSelector and the built-in validators understand.
+const (
+	AttrRegion      = "region"
+	AttrZone        = "zone"
+	AttrRack        = "rack"
+	AttrLocode      = "locode"
+	AttrRole        = "role"
+	AttrCoordinates = "coordinates"
+)
+
+// AttributeValidator checks whether a proposed value for an attribute key is well-formed.
+// Validators are consulted by SetNodeAttribute before the value is persisted.
+type AttributeValidator interface {
+	// Validate returns an error if value is not a valid value for the given attribute key.
+	// existing holds the node's other already-persisted attributes, for validators (like
+	// LocodeValidator) that need to cross-check one attribute against another.
+	Validate(key, value string, existing map[string]string) error
+}
+
+func getNodeAttrKey(nodeID, key string) string {
+	return path.Join(GetNodeConfigKey(nodeID), AttrsKey, key)
+}
+
+// SetNodeAttribute sets a single placement attribute on a node. If validator is non-nil, the
+// value is validated before being persisted, against the backdrop of the node's other
+// already-set attributes.
+func SetNodeAttribute(etcdClient etcd.KeysAPI, nodeID, key, value string, validator AttributeValidator) error {
+	if validator != nil {
+		existing, err := ListNodeAttributes(etcdClient, nodeID)
+		if err != nil {
+			return err
+		}
+
+		if err := validator.Validate(key, value, existing); err != nil {
+			return fmt.Errorf("invalid value %q for attribute %s: %v", value, key, err)
+		}
+	}
+
+	_, err := etcdClient.Set(ctx.Background(), getNodeAttrKey(nodeID, key), value, nil)
+	return err
+}
+
+// GetNodeAttribute gets a single placement attribute from a node.
+func GetNodeAttribute(etcdClient etcd.KeysAPI, nodeID, key string) (string, error) {
+	resp, err := etcdClient.Get(ctx.Background(), getNodeAttrKey(nodeID, key), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Node.Value, nil
+}
+
+// ListNodeAttributes returns all placement attributes set on a node.
+func ListNodeAttributes(etcdClient etcd.KeysAPI, nodeID string) (map[string]string, error) {
+	key := path.Join(GetNodeConfigKey(nodeID), AttrsKey)
+	resp, err := etcdClient.Get(ctx.Background(), key, &etcd.GetOptions{Recursive: true})
+	if err != nil {
+		if util.IsEtcdKeyNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	attrs := make(map[string]string, len(resp.Node.Nodes))
+	for _, attrNode := range resp.Node.Nodes {
+		attrs[util.GetLeafKeyPath(attrNode.Key)] = attrNode.Value
+	}
+
+	return attrs, nil
+}
+
+// loadAttributesConfig parses the attrs subtree of a node's etcd config into the NodeConfig.
+func loadAttributesConfig(nodeConfig *NodeConfig, attrsRootNode *etcd.Node) error {
+	attrs := make(map[string]string, len(attrsRootNode.Nodes))
+	for _, attrNode := range attrsRootNode.Nodes {
+		attrs[util.GetLeafKeyPath(attrNode.Key)] = attrNode.Value
+	}
+
+	nodeConfig.Attributes = attrs
+	return nil
+}
@@ -0,0 +1,38 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetGetListNodeAttribute(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+
+	assert.Nil(t, SetNodeAttribute(etcdClient, "node1", AttrRegion, "us-east", nil))
+	assert.Nil(t, SetNodeAttribute(etcdClient, "node1", AttrRack, "r1", nil))
+
+	value, err := GetNodeAttribute(etcdClient, "node1", AttrRegion)
+	assert.Nil(t, err)
+	assert.Equal(t, "us-east", value)
+
+	attrs, err := ListNodeAttributes(etcdClient, "node1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{AttrRegion: "us-east", AttrRack: "r1"}, attrs)
+}
+
+func TestSetNodeAttributeValidation(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+
+	assert.NotNil(t, SetNodeAttribute(etcdClient, "node1", AttrLocode, "bogus", LocodeValidator{}))
+	assert.Nil(t, SetNodeAttribute(etcdClient, "node1", AttrLocode, "US NYC", LocodeValidator{}))
+}
+
+func TestSetNodeAttributeValidatesCoordinatesAgainstLocode(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+
+	assert.Nil(t, SetNodeAttribute(etcdClient, "node1", AttrLocode, "US NYC", LocodeValidator{}))
+	assert.NotNil(t, SetNodeAttribute(etcdClient, "node1", AttrCoordinates, "51.5,-0.1", LocodeValidator{}),
+		"london coordinates shouldn't validate against a US locode already set on the node")
+	assert.Nil(t, SetNodeAttribute(etcdClient, "node1", AttrCoordinates, "40.7,-74.0", LocodeValidator{}))
+}
@@ -0,0 +1,259 @@
+package inventory
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/quantum/castle/pkg/util"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// watchRetryBackoff is how long the watch loop waits before retrying after a reseed failure or
+// any other watch error, so a persistent etcd outage doesn't spin the goroutine in a tight loop.
+const watchRetryBackoff = time.Second
+
+// InventoryEventKind describes what changed about a node in an InventoryEvent.
+type InventoryEventKind int
+
+const (
+	// NodeAdded is emitted the first time a node's hardware is discovered.
+	NodeAdded InventoryEventKind = iota
+	// NodeUpdated is emitted when any other part of an already-known node's config changes,
+	// e.g. its IP address, lifecycle state, or attributes.
+	NodeUpdated
+	// NodeRemoved is emitted when a node's discovered config is deleted from etcd.
+	NodeRemoved
+	// NodeHardwareChanged is emitted when a node's disks, processors, memory, or network
+	// adapters change, e.g. a new disk key appears under an already-known node.
+	NodeHardwareChanged
+)
+
+// InventoryEvent describes a single change to a node's inventory, carrying both the config
+// before and after the change (OldNode is nil for NodeAdded, NewNode is nil for NodeRemoved).
+type InventoryEvent struct {
+	Kind    InventoryEventKind
+	NodeID  string
+	OldNode *NodeConfig
+	NewNode *NodeConfig
+}
+
+// InventoryCache is a long-lived, watch-driven view of the discovered node inventory. It seeds
+// itself by loading every discovered node once (see seed) and then incrementally applies etcd
+// watch events, so callers no longer need to pay for a full recursive Get on every read.
+type InventoryCache struct {
+	etcdClient etcd.KeysAPI
+
+	mutex sync.RWMutex
+	nodes map[string]*NodeConfig
+
+	subMutex    sync.Mutex
+	subscribers []chan InventoryEvent
+}
+
+// NewInventoryCache creates an InventoryCache, seeds it from etcd, and starts watching for
+// changes in the background.
+func NewInventoryCache(etcdClient etcd.KeysAPI) (*InventoryCache, error) {
+	c := &InventoryCache{etcdClient: etcdClient, nodes: map[string]*NodeConfig{}}
+
+	index, err := c.seed()
+	if err != nil {
+		return nil, err
+	}
+
+	go c.watch(index)
+
+	return c, nil
+}
+
+// Snapshot returns a point-in-time copy of the cached inventory.
+func (c *InventoryCache) Snapshot() map[string]*NodeConfig {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	snapshot := make(map[string]*NodeConfig, len(c.nodes))
+	for nodeID, nodeConfig := range c.nodes {
+		snapshot[nodeID] = nodeConfig
+	}
+
+	return snapshot
+}
+
+// Get returns the cached config for a single node, and whether it was found.
+func (c *InventoryCache) Get(nodeID string) (*NodeConfig, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	nodeConfig, ok := c.nodes[nodeID]
+	return nodeConfig, ok
+}
+
+// Subscribe returns a channel of InventoryEvents. The channel is buffered; a slow consumer
+// drops events rather than blocking the cache's watch loop.
+func (c *InventoryCache) Subscribe() <-chan InventoryEvent {
+	ch := make(chan InventoryEvent, 32)
+
+	c.subMutex.Lock()
+	c.subscribers = append(c.subscribers, ch)
+	c.subMutex.Unlock()
+
+	return ch
+}
+
+// seed lists the node IDs under DiscoveredNodesKey and populates the cache by loading each node
+// with its own Gets (via loadOneNodeConfig). It then does one recursive Get on
+// DiscoveredNodesKey, whose response is used only for the etcd index returned to resume
+// watching from; the node configs are not built from that response.
+func (c *InventoryCache) seed() (uint64, error) {
+	nodes, err := util.GetDirChildKeys(c.etcdClient, DiscoveredNodesKey)
+	if err != nil {
+		return 0, err
+	}
+
+	nodesConfig := make(map[string]*NodeConfig)
+	for node := range nodes.Iter() {
+		nodeConfig, err := loadOneNodeConfig(c.etcdClient, node)
+		if err != nil {
+			return 0, err
+		}
+		if nodeConfig != nil {
+			nodesConfig[node] = nodeConfig
+		}
+	}
+
+	resp, err := c.etcdClient.Get(ctx.Background(), DiscoveredNodesKey, &etcd.GetOptions{Recursive: true})
+	index := uint64(0)
+	if err == nil {
+		index = resp.Index
+	} else if !util.IsEtcdKeyNotFound(err) {
+		return 0, err
+	}
+
+	c.mutex.Lock()
+	c.nodes = nodesConfig
+	c.mutex.Unlock()
+
+	return index, nil
+}
+
+// watch consumes etcd watch events starting at afterIndex and applies them to the cache until
+// the watcher reports that its index has been outdated, in which case it reseeds and resumes.
+func (c *InventoryCache) watch(afterIndex uint64) {
+	watcher := c.etcdClient.Watcher(DiscoveredNodesKey, &etcd.WatcherOptions{Recursive: true, AfterIndex: afterIndex})
+
+	for {
+		resp, err := watcher.Next(ctx.Background())
+		if err != nil {
+			if isIndexOutdatedErr(err) {
+				index, seedErr := c.seed()
+				if seedErr != nil {
+					log.Printf("failed to reseed inventory cache, err=%v", seedErr)
+					time.Sleep(watchRetryBackoff)
+					continue
+				}
+				watcher = c.etcdClient.Watcher(DiscoveredNodesKey, &etcd.WatcherOptions{Recursive: true, AfterIndex: index})
+				continue
+			}
+
+			log.Printf("inventory cache watch error, err=%v", err)
+			time.Sleep(watchRetryBackoff)
+			continue
+		}
+
+		c.applyEvent(resp)
+	}
+}
+
+// applyEvent reloads the node named in resp and publishes the appropriate InventoryEvent.
+func (c *InventoryCache) applyEvent(resp *etcd.Response) {
+	nodeID := nodeIDFromKey(resp.Node.Key)
+	if nodeID == "" {
+		return
+	}
+
+	c.mutex.RLock()
+	oldNode := c.nodes[nodeID]
+	c.mutex.RUnlock()
+
+	// Always reload rather than trusting resp.Action: a delete/expire on this node's subtree
+	// can be a single sub-property disappearing (e.g. one disk, one attribute), not the whole
+	// node. loadOneNodeConfig returns a nil NodeConfig only when the node is genuinely gone.
+	newNode, err := loadOneNodeConfig(c.etcdClient, nodeID)
+	if err != nil {
+		log.Printf("failed to reload node %s after watch event, err=%v", nodeID, err)
+		return
+	}
+
+	c.mutex.Lock()
+	if newNode == nil {
+		delete(c.nodes, nodeID)
+	} else {
+		c.nodes[nodeID] = newNode
+	}
+	c.mutex.Unlock()
+
+	c.publish(InventoryEvent{Kind: eventKind(oldNode, newNode, resp.Node.Key, nodeID), NodeID: nodeID, OldNode: oldNode, NewNode: newNode})
+}
+
+// eventKind classifies a change based on whether the node is new, removed, or already known,
+// and whether the changed key lives under a hardware subtree of an already-known node.
+func eventKind(oldNode, newNode *NodeConfig, changedKey, nodeID string) InventoryEventKind {
+	if newNode == nil {
+		return NodeRemoved
+	}
+	if oldNode == nil {
+		return NodeAdded
+	}
+
+	leaf := strings.TrimPrefix(changedKey, GetNodeConfigKey(nodeID)+"/")
+	switch {
+	case strings.HasPrefix(leaf, DisksKey+"/"), strings.HasPrefix(leaf, ProcessorsKey+"/"),
+		strings.HasPrefix(leaf, MemoryKey+"/"), strings.HasPrefix(leaf, NetworkKey+"/"):
+		return NodeHardwareChanged
+	default:
+		return NodeUpdated
+	}
+}
+
+// nodeIDFromKey extracts the node ID from a key somewhere under DiscoveredNodesKey.
+func nodeIDFromKey(key string) string {
+	rest := strings.TrimPrefix(key, DiscoveredNodesKey+"/")
+	if rest == key {
+		return ""
+	}
+
+	return strings.SplitN(rest, "/", 2)[0]
+}
+
+// publish fans an event out to all current subscribers without blocking on slow readers.
+func (c *InventoryCache) publish(event InventoryEvent) {
+	c.subMutex.Lock()
+	defer c.subMutex.Unlock()
+
+	for _, ch := range c.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("inventory cache subscriber channel full, dropping event for node %s", event.NodeID)
+		}
+	}
+}
+
+// isIndexOutdatedErr returns true if err indicates the watcher's requested index has already
+// been compacted out of etcd's event history, requiring a reseed.
+func isIndexOutdatedErr(err error) bool {
+	etcdErr, ok := err.(etcd.Error)
+	if !ok {
+		if p, ok := err.(*etcd.Error); ok {
+			etcdErr = *p
+		} else {
+			return false
+		}
+	}
+
+	return etcdErr.Code == etcd.ErrorCodeEventIndexCleared
+}
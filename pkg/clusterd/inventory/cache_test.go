@@ -0,0 +1,92 @@
+package inventory
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInventoryCacheHardwareChanged verifies that an OSD-placement-style consumer watching the
+// cache's Subscribe() channel sees a NodeHardwareChanged event when a new disk key appears
+// under a node it already knows about.
+func TestInventoryCacheHardwareChanged(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+	nodeConfigKey := GetNodeConfigKey("node1")
+
+	_, err := etcdClient.Set(ctx.Background(), path.Join(nodeConfigKey, IpAddressKey), "10.0.0.1", nil)
+	assert.Nil(t, err)
+
+	cache, err := NewInventoryCache(etcdClient)
+	assert.Nil(t, err)
+
+	events := cache.Subscribe()
+
+	_, err = etcdClient.Set(ctx.Background(), path.Join(nodeConfigKey, DisksKey, "sda", DiskSizeKey), "1024", nil)
+	assert.Nil(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, NodeHardwareChanged, event.Kind)
+		assert.Equal(t, "node1", event.NodeID)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for inventory event")
+	}
+}
+
+func TestInventoryCacheSnapshotAndGet(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+	nodeConfigKey := GetNodeConfigKey("node1")
+	_, err := etcdClient.Set(ctx.Background(), path.Join(nodeConfigKey, IpAddressKey), "10.0.0.1", nil)
+	assert.Nil(t, err)
+
+	cache, err := NewInventoryCache(etcdClient)
+	assert.Nil(t, err)
+
+	nodeConfig, ok := cache.Get("node1")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1", nodeConfig.IPAddress)
+
+	snapshot := cache.Snapshot()
+	assert.Len(t, snapshot, 1)
+
+	_, ok = cache.Get("node2")
+	assert.False(t, ok)
+}
+
+// TestInventoryCacheSubPropertyDeleteKeepsNode verifies that deleting a single sub-property
+// (here, one disk) reloads the node rather than dropping it from the cache entirely, since an
+// etcd delete event on a node's subtree doesn't necessarily mean the whole node is gone.
+func TestInventoryCacheSubPropertyDeleteKeepsNode(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+	nodeConfigKey := GetNodeConfigKey("node1")
+
+	_, err := etcdClient.Set(ctx.Background(), path.Join(nodeConfigKey, IpAddressKey), "10.0.0.1", nil)
+	assert.Nil(t, err)
+	diskSizeKey := path.Join(nodeConfigKey, DisksKey, "sda", DiskSizeKey)
+	_, err = etcdClient.Set(ctx.Background(), diskSizeKey, "1024", nil)
+	assert.Nil(t, err)
+
+	cache, err := NewInventoryCache(etcdClient)
+	assert.Nil(t, err)
+
+	events := cache.Subscribe()
+
+	_, err = etcdClient.Delete(ctx.Background(), diskSizeKey, nil)
+	assert.Nil(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, NodeHardwareChanged, event.Kind)
+		assert.Equal(t, "node1", event.NodeID)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for inventory event")
+	}
+
+	nodeConfig, ok := cache.Get("node1")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1", nodeConfig.IPAddress)
+}
@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/quantum/castle/pkg/proc"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// discoverProcessorsConfig enumerates the logical processors on the local node by reading
+// /proc/cpuinfo directly and persists them under nodeConfigKey/cpu. Each "key : value" line of
+// /proc/cpuinfo is a single field, so unlike the old ad-hoc kv parsing this never splits a
+// multi-word value like a CPU model name on its internal spaces.
+func discoverProcessorsConfig(nodeConfigKey string, etcdClient etcd.KeysAPI, executor proc.Executor) error {
+	out, err := executor.ExecuteCommandPipeline("cat /proc/cpuinfo", nil)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range parseCpuinfo(out) {
+		procKey := path.Join(nodeConfigKey, ProcessorsKey, strconv.FormatUint(uint64(p.ID), 10))
+		props := map[string]string{
+			ProcPhysicalIDKey: strconv.FormatUint(uint64(p.PhysicalID), 10),
+			ProcSiblingsKey:   strconv.FormatUint(uint64(p.Siblings), 10),
+			ProcCoreIDKey:     strconv.FormatUint(uint64(p.CoreID), 10),
+			ProcNumCoresKey:   strconv.FormatUint(uint64(p.NumCores), 10),
+			ProcSpeedKey:      strconv.FormatFloat(p.Speed, 'f', -1, 64),
+			ProcBitsKey:       strconv.FormatUint(uint64(p.Bits), 10),
+			ProcModelNameKey:  p.ModelName,
+			ProcFlagsKey:      strings.Join(p.Flags, " "),
+			ProcCacheKBKey:    strconv.FormatUint(uint64(p.CacheKB), 10),
+		}
+
+		for propKey, propValue := range props {
+			if _, err := etcdClient.Set(ctx.Background(), path.Join(procKey, propKey), propValue, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseCpuinfo parses the contents of /proc/cpuinfo, which is a sequence of per-processor
+// blocks of "key\t: value" lines separated by blank lines, into ProcessorConfigs.
+func parseCpuinfo(raw string) []ProcessorConfig {
+	var procs []ProcessorConfig
+	var cur *ProcessorConfig
+
+	flush := func() {
+		if cur != nil {
+			procs = append(procs, *cur)
+			cur = nil
+		}
+	}
+
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+
+		if key == "processor" {
+			flush()
+			cur = &ProcessorConfig{}
+			if id, err := strconv.ParseUint(value, 10, 32); err == nil {
+				cur.ID = uint(id)
+			}
+			continue
+		}
+
+		if cur == nil {
+			continue
+		}
+
+		switch key {
+		case "physical id":
+			if v, err := strconv.ParseUint(value, 10, 32); err == nil {
+				cur.PhysicalID = uint(v)
+			}
+		case "siblings":
+			if v, err := strconv.ParseUint(value, 10, 32); err == nil {
+				cur.Siblings = uint(v)
+			}
+		case "core id":
+			if v, err := strconv.ParseUint(value, 10, 32); err == nil {
+				cur.CoreID = uint(v)
+			}
+		case "cpu cores":
+			if v, err := strconv.ParseUint(value, 10, 32); err == nil {
+				cur.NumCores = uint(v)
+			}
+		case "cpu MHz":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				cur.Speed = v
+			}
+		case "model name":
+			cur.ModelName = value
+		case "flags":
+			cur.Flags = strings.Fields(value)
+		case "cache size":
+			// e.g. "8192 KB"
+			if fields := strings.Fields(value); len(fields) > 0 {
+				if v, err := strconv.ParseUint(fields[0], 10, 32); err == nil {
+					cur.CacheKB = uint(v)
+				}
+			}
+		case "address sizes":
+			// e.g. "46 bits physical, 48 bits virtual": a virtual address width above 32 bits
+			// means a 64-bit processor.
+			cur.Bits = 32
+			for _, part := range strings.Split(value, ",") {
+				fields := strings.Fields(part)
+				if len(fields) == 3 && fields[2] == "virtual" {
+					if v, err := strconv.ParseUint(fields[0], 10, 32); err == nil && v > 32 {
+						cur.Bits = 64
+					}
+				}
+			}
+		}
+	}
+	flush()
+
+	return procs
+}
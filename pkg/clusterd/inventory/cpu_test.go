@@ -0,0 +1,38 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCpuinfo(t *testing.T) {
+	raw := "processor\t: 0\n" +
+		"physical id\t: 0\n" +
+		"core id\t: 0\n" +
+		"cpu cores\t: 4\n" +
+		"siblings\t: 8\n" +
+		"cpu MHz\t: 2400.000\n" +
+		"cache size\t: 8192 KB\n" +
+		"model name\t: Intel(R) Xeon(R) CPU E5-2680 v4 @ 2.40GHz\n" +
+		"flags\t: fpu vme de pse\n" +
+		"address sizes\t: 46 bits physical, 48 bits virtual\n" +
+		"\n" +
+		"processor\t: 1\n" +
+		"physical id\t: 0\n" +
+		"core id\t: 1\n" +
+		"cpu cores\t: 4\n" +
+		"\n"
+
+	procs := parseCpuinfo(raw)
+	assert.Len(t, procs, 2)
+
+	assert.Equal(t, uint(0), procs[0].ID)
+	assert.Equal(t, "Intel(R) Xeon(R) CPU E5-2680 v4 @ 2.40GHz", procs[0].ModelName)
+	assert.Equal(t, []string{"fpu", "vme", "de", "pse"}, procs[0].Flags)
+	assert.Equal(t, uint(8192), procs[0].CacheKB)
+	assert.Equal(t, uint(64), procs[0].Bits)
+
+	assert.Equal(t, uint(1), procs[1].ID)
+	assert.Equal(t, uint(0), procs[1].PhysicalID)
+}
@@ -0,0 +1,146 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/quantum/castle/pkg/proc"
+	"github.com/quantum/castle/pkg/util"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+const (
+	DiskUUIDKey       = "uuid"
+	DiskSizeKey       = "size"
+	DiskRotationalKey = "rotational"
+	DiskModelKey      = "model"
+	DiskVendorKey     = "vendor"
+	DiskSerialKey     = "serial"
+	DiskWWNKey        = "wwn"
+	DiskTransportKey  = "transport"
+)
+
+// lshwDiskNode is the subset of `lshw -json -class disk` output this package cares about.
+type lshwDiskNode struct {
+	LogicalName   string            `json:"logicalname"`
+	Product       string            `json:"product"`
+	Vendor        string            `json:"vendor"`
+	Serial        string            `json:"serial"`
+	Size          uint64            `json:"size"`
+	Configuration map[string]string `json:"configuration"`
+}
+
+// discoverDisks enumerates the disks on the local node via `lshw -json -class disk` and
+// persists them under nodeConfigKey/disks. lshw's structured JSON output is decoded directly
+// into lshwDiskNode, so free-text fields are never split on whitespace and can safely contain
+// spaces of their own.
+func discoverDisks(nodeConfigKey string, etcdClient etcd.KeysAPI, executor proc.Executor) error {
+	disks, err := probeDisks(executor)
+	if err != nil {
+		return err
+	}
+
+	for _, disk := range disks {
+		diskKey := path.Join(nodeConfigKey, DisksKey, disk.Name)
+		props := map[string]string{
+			DiskSizeKey:       strconv.FormatUint(disk.Size, 10),
+			DiskRotationalKey: strconv.FormatBool(disk.Rotational),
+			DiskModelKey:      disk.Model,
+			DiskVendorKey:     disk.Vendor,
+			DiskSerialKey:     disk.Serial,
+			DiskWWNKey:        disk.WWN,
+			DiskTransportKey:  disk.Transport,
+		}
+
+		for propKey, propValue := range props {
+			if _, err := etcdClient.Set(ctx.Background(), path.Join(diskKey, propKey), propValue, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// probeDisks runs lshw against the local node and unmarshals its JSON output into DiskConfigs.
+func probeDisks(executor proc.Executor) ([]DiskConfig, error) {
+	out, err := executor.ExecuteCommandPipeline("lshw -json -class disk", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var lshwDisks []lshwDiskNode
+	if err := json.Unmarshal([]byte(out), &lshwDisks); err != nil {
+		return nil, fmt.Errorf("failed to parse lshw disk output: %v", err)
+	}
+
+	disks := make([]DiskConfig, 0, len(lshwDisks))
+	for _, n := range lshwDisks {
+		disks = append(disks, DiskConfig{
+			Name:       path.Base(n.LogicalName),
+			Size:       n.Size,
+			Model:      n.Product,
+			Vendor:     n.Vendor,
+			Serial:     n.Serial,
+			Rotational: n.Configuration["rotational"] == "1",
+			WWN:        n.Configuration["wwn"],
+			Transport:  diskTransport(n.Configuration["driver"]),
+		})
+	}
+
+	return disks, nil
+}
+
+// diskTransport maps the driver name lshw reports to one of the coarse transport categories
+// callers care about for placement decisions.
+func diskTransport(driver string) string {
+	driver = strings.ToLower(driver)
+	switch {
+	case strings.Contains(driver, "nvme"):
+		return "nvme"
+	case strings.Contains(driver, "sas"):
+		return "sas"
+	default:
+		return "sata"
+	}
+}
+
+// GetDiskInfo parses a single disk's etcd subtree into a DiskConfig. It understands both the
+// original flat keys (uuid/size/rotational) and the richer keys added for structured hardware
+// discovery, so inventory recorded before that migration is still read correctly.
+func GetDiskInfo(diskInfo *etcd.Node) (*DiskConfig, error) {
+	disk := &DiskConfig{Name: util.GetLeafKeyPath(diskInfo.Key)}
+
+	for _, prop := range diskInfo.Nodes {
+		switch util.GetLeafKeyPath(prop.Key) {
+		case DiskUUIDKey:
+			disk.UUID = prop.Value
+		case DiskSizeKey:
+			size, err := strconv.ParseUint(prop.Value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			disk.Size = size
+		case DiskRotationalKey:
+			disk.Rotational = prop.Value == "true"
+		case DiskModelKey:
+			disk.Model = prop.Value
+		case DiskVendorKey:
+			disk.Vendor = prop.Value
+		case DiskSerialKey:
+			disk.Serial = prop.Value
+		case DiskWWNKey:
+			disk.WWN = prop.Value
+		case DiskTransportKey:
+			disk.Transport = prop.Value
+		}
+	}
+
+	return disk, nil
+}
@@ -0,0 +1,29 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeDisks(t *testing.T) {
+	executor := &fakeExecutor{output: `[
+		{"logicalname": "/dev/sda", "product": "Samsung SSD 970", "vendor": "Samsung", "serial": "S123456",
+		 "size": 512110190592, "configuration": {"rotational": "0", "wwn": "0x5002538abcdef123", "driver": "nvme"}},
+		{"logicalname": "/dev/sdb", "product": "ST4000", "vendor": "Seagate", "serial": "Z987654",
+		 "size": 4000787030016, "configuration": {"rotational": "1", "driver": "sd"}}
+	]`}
+
+	disks, err := probeDisks(executor)
+	assert.Nil(t, err)
+	assert.Len(t, disks, 2)
+
+	assert.Equal(t, "sda", disks[0].Name)
+	assert.Equal(t, "Samsung SSD 970", disks[0].Model)
+	assert.False(t, disks[0].Rotational)
+	assert.Equal(t, "nvme", disks[0].Transport)
+
+	assert.Equal(t, "sdb", disks[1].Name)
+	assert.True(t, disks[1].Rotational)
+	assert.Equal(t, "sata", disks[1].Transport)
+}
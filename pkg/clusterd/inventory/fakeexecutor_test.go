@@ -0,0 +1,13 @@
+package inventory
+
+// fakeExecutor is a minimal proc.Executor stand-in that returns a fixed, pre-recorded string
+// for ExecuteCommandPipeline, regardless of the command given to it. It lets tests exercise the
+// parsing side of hardware discovery without actually shelling out.
+type fakeExecutor struct {
+	output string
+	err    error
+}
+
+func (e *fakeExecutor) ExecuteCommandPipeline(command string, args []string) (string, error) {
+	return e.output, e.err
+}
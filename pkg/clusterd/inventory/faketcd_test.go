@@ -0,0 +1,175 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	ctx "golang.org/x/net/context"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// fakeKeysAPI is a minimal in-memory implementation of etcd.KeysAPI sufficient for exercising
+// the inventory package's get/set/watch logic without a real etcd cluster.
+type fakeKeysAPI struct {
+	mutex  sync.Mutex
+	values map[string]string
+	index  uint64
+
+	watchersMutex sync.Mutex
+	watchers      []*fakeWatcher
+}
+
+func newFakeKeysAPI() *fakeKeysAPI {
+	return &fakeKeysAPI{values: make(map[string]string)}
+}
+
+func (f *fakeKeysAPI) Get(_ ctx.Context, key string, opts *etcd.GetOptions) (*etcd.Response, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key = strings.TrimSuffix(key, "/")
+	recursive := opts != nil && opts.Recursive
+
+	node, found := f.buildNode(key, recursive)
+	if !found {
+		return nil, fakeKeyNotFoundError(key)
+	}
+
+	return &etcd.Response{Node: node, Index: f.index}, nil
+}
+
+func (f *fakeKeysAPI) Set(_ ctx.Context, key, value string, _ *etcd.SetOptions) (*etcd.Response, error) {
+	f.mutex.Lock()
+	f.values[strings.TrimSuffix(key, "/")] = value
+	f.index++
+	node := &etcd.Node{Key: key, Value: value, ModifiedIndex: f.index}
+	idx := f.index
+	f.mutex.Unlock()
+
+	f.notify("set", node, idx)
+	return &etcd.Response{Action: "set", Node: node, Index: idx}, nil
+}
+
+func (f *fakeKeysAPI) Delete(_ ctx.Context, key string, _ *etcd.DeleteOptions) (*etcd.Response, error) {
+	f.mutex.Lock()
+	key = strings.TrimSuffix(key, "/")
+	prefix := key + "/"
+	for k := range f.values {
+		if k == key || strings.HasPrefix(k, prefix) {
+			delete(f.values, k)
+		}
+	}
+	f.index++
+	node := &etcd.Node{Key: key, ModifiedIndex: f.index}
+	idx := f.index
+	f.mutex.Unlock()
+
+	f.notify("delete", node, idx)
+	return &etcd.Response{Action: "delete", Node: node, Index: idx}, nil
+}
+
+func (f *fakeKeysAPI) Create(c ctx.Context, key, value string) (*etcd.Response, error) {
+	return f.Set(c, key, value, nil)
+}
+
+func (f *fakeKeysAPI) CreateInOrder(_ ctx.Context, _, _ string, _ *etcd.CreateInOrderOptions) (*etcd.Response, error) {
+	return nil, fmt.Errorf("CreateInOrder not supported by fakeKeysAPI")
+}
+
+func (f *fakeKeysAPI) Update(c ctx.Context, key, value string) (*etcd.Response, error) {
+	return f.Set(c, key, value, nil)
+}
+
+func (f *fakeKeysAPI) Watcher(key string, opts *etcd.WatcherOptions) etcd.Watcher {
+	w := &fakeWatcher{key: strings.TrimSuffix(key, "/"), events: make(chan *etcd.Response, 16)}
+	if opts != nil {
+		w.recursive = opts.Recursive
+	}
+
+	f.watchersMutex.Lock()
+	f.watchers = append(f.watchers, w)
+	f.watchersMutex.Unlock()
+
+	return w
+}
+
+// buildNode reconstructs an etcd.Node tree for key out of the flat value map.
+func (f *fakeKeysAPI) buildNode(key string, recursive bool) (*etcd.Node, bool) {
+	if value, ok := f.values[key]; ok {
+		return &etcd.Node{Key: key, Value: value}, true
+	}
+
+	prefix := key + "/"
+	childKeys := map[string]bool{}
+	any := false
+	for k := range f.values {
+		if strings.HasPrefix(k, prefix) {
+			any = true
+			rest := strings.TrimPrefix(k, prefix)
+			childKeys[prefix+strings.SplitN(rest, "/", 2)[0]] = true
+		}
+	}
+	if !any {
+		return nil, false
+	}
+
+	var names []string
+	for k := range childKeys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	node := &etcd.Node{Key: key, Dir: true}
+	for _, childKey := range names {
+		if recursive {
+			child, _ := f.buildNode(childKey, true)
+			node.Nodes = append(node.Nodes, child)
+		} else {
+			node.Nodes = append(node.Nodes, &etcd.Node{Key: childKey, Dir: true})
+		}
+	}
+
+	return node, true
+}
+
+func (f *fakeKeysAPI) notify(action string, node *etcd.Node, index uint64) {
+	f.watchersMutex.Lock()
+	defer f.watchersMutex.Unlock()
+
+	for _, w := range f.watchers {
+		if w.recursive {
+			if node.Key != w.key && !strings.HasPrefix(node.Key, w.key+"/") {
+				continue
+			}
+		} else if node.Key != w.key {
+			continue
+		}
+
+		select {
+		case w.events <- &etcd.Response{Action: action, Node: node, Index: index}:
+		default:
+		}
+	}
+}
+
+type fakeWatcher struct {
+	key       string
+	recursive bool
+	events    chan *etcd.Response
+}
+
+func (w *fakeWatcher) Next(c ctx.Context) (*etcd.Response, error) {
+	select {
+	case resp := <-w.events:
+		return resp, nil
+	case <-c.Done():
+		return nil, c.Err()
+	}
+}
+
+func fakeKeyNotFoundError(key string) error {
+	return &etcd.Error{Code: etcd.ErrorCodeKeyNotFound, Cause: key, Message: "Key not found"}
+}
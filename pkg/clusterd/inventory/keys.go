@@ -0,0 +1,36 @@
+package inventory
+
+// DiscoveredNodesKey is the etcd key under which all discovered node hardware/config is stored.
+const DiscoveredNodesKey = "/castle/nodes"
+
+const (
+	ProcPhysicalIDKey = "physicalid"
+	ProcSiblingsKey   = "siblings"
+	ProcCoreIDKey     = "coreid"
+	ProcNumCoresKey   = "numcores"
+	ProcSpeedKey      = "speed"
+	ProcBitsKey       = "bits"
+	ProcModelNameKey  = "modelname"
+	ProcFlagsKey      = "flags"
+	ProcCacheKBKey    = "cachekb"
+)
+
+const MemoryTotalSizeKey = "totalsize"
+
+// MemoryDimmsKey is the subkey under a node's mem config holding one entry per physical DIMM.
+const MemoryDimmsKey = "dimms"
+
+const (
+	DimmSizeBytesKey    = "sizebytes"
+	DimmSpeedMHzKey     = "speedmhz"
+	DimmManufacturerKey = "manufacturer"
+)
+
+const (
+	NetworkIPv4AddressKey = "ipv4address"
+	NetworkIPv6AddressKey = "ipv6address"
+	NetworkSpeedKey       = "speed"
+	NetworkMACKey         = "mac"
+	NetworkMTUKey         = "mtu"
+	NetworkOperStateKey   = "operstate"
+)
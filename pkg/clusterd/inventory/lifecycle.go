@@ -0,0 +1,93 @@
+package inventory
+
+import (
+	"path"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/quantum/castle/pkg/util"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// NodeLifecycleState describes where a node is in its drain/decommission lifecycle.
+// Schedulers use this state to decide whether new OSDs/mons may be placed on a node
+// and whether data needs to be evacuated from it.
+type NodeLifecycleState string
+
+const (
+	// NodeLifecycleReady means the node is schedulable as normal.
+	NodeLifecycleReady NodeLifecycleState = "ready"
+	// NodeLifecycleCordoned means the node is marked unschedulable but still serving existing data.
+	NodeLifecycleCordoned NodeLifecycleState = "cordoned"
+	// NodeLifecycleDraining means data is actively being evacuated off the node.
+	NodeLifecycleDraining NodeLifecycleState = "draining"
+	// NodeLifecycleDecommissioned means the node has been fully evacuated and is awaiting removal.
+	NodeLifecycleDecommissioned NodeLifecycleState = "decommissioned"
+)
+
+const (
+	// LifecycleKey is the subkey under a node's config where lifecycle state is stored.
+	LifecycleKey = "lifecycle"
+
+	lifecycleStateKey = "state"
+)
+
+// getLifecycleStateKey gets the etcd key for a node's lifecycle state.
+func getLifecycleStateKey(nodeID string) string {
+	return path.Join(GetNodeConfigKey(nodeID), LifecycleKey, lifecycleStateKey)
+}
+
+// SetNodeLifecycleState sets the lifecycle state for a node. This is scheduler-owned state:
+// it is never touched by DiscoverHardware, so it survives client-driven rediscovery upserts.
+func SetNodeLifecycleState(etcdClient etcd.KeysAPI, nodeID string, state NodeLifecycleState) error {
+	_, err := etcdClient.Set(ctx.Background(), getLifecycleStateKey(nodeID), string(state), nil)
+	return err
+}
+
+// GetNodeLifecycleState gets the lifecycle state for a node. A node with no lifecycle state
+// recorded yet is considered Ready.
+func GetNodeLifecycleState(etcdClient etcd.KeysAPI, nodeID string) (NodeLifecycleState, error) {
+	resp, err := etcdClient.Get(ctx.Background(), getLifecycleStateKey(nodeID), nil)
+	if err != nil {
+		if util.IsEtcdKeyNotFound(err) {
+			return NodeLifecycleReady, nil
+		}
+		return "", err
+	}
+
+	return NodeLifecycleState(resp.Node.Value), nil
+}
+
+// SetNodeDrain cordons or uncordons a node. A cordoned node should not receive new OSD or mon
+// placements, but continues serving the data it already holds.
+func SetNodeDrain(etcdClient etcd.KeysAPI, nodeID string, drain bool) error {
+	if drain {
+		return SetNodeLifecycleState(etcdClient, nodeID, NodeLifecycleCordoned)
+	}
+
+	return SetNodeLifecycleState(etcdClient, nodeID, NodeLifecycleReady)
+}
+
+// GetNodeDrain returns true if the node is cordoned, draining, or decommissioned, i.e. it is
+// not currently a candidate for new placements.
+func GetNodeDrain(etcdClient etcd.KeysAPI, nodeID string) (bool, error) {
+	state, err := GetNodeLifecycleState(etcdClient, nodeID)
+	if err != nil {
+		return false, err
+	}
+
+	return state != NodeLifecycleReady, nil
+}
+
+// loadLifecycleConfig parses the lifecycle subtree of a node's etcd config into the NodeConfig.
+func loadLifecycleConfig(nodeConfig *NodeConfig, lifecycleRootNode *etcd.Node) error {
+	for _, lifecycleProperty := range lifecycleRootNode.Nodes {
+		switch util.GetLeafKeyPath(lifecycleProperty.Key) {
+		case lifecycleStateKey:
+			nodeConfig.LifecycleState = NodeLifecycleState(lifecycleProperty.Value)
+		}
+	}
+
+	return nil
+}
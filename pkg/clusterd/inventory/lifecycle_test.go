@@ -0,0 +1,43 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetGetNodeDrain(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+
+	drain, err := GetNodeDrain(etcdClient, "node1")
+	assert.Nil(t, err)
+	assert.False(t, drain)
+
+	assert.Nil(t, SetNodeDrain(etcdClient, "node1", true))
+
+	drain, err = GetNodeDrain(etcdClient, "node1")
+	assert.Nil(t, err)
+	assert.True(t, drain)
+
+	state, err := GetNodeLifecycleState(etcdClient, "node1")
+	assert.Nil(t, err)
+	assert.Equal(t, NodeLifecycleCordoned, state)
+}
+
+// TestDiscoverHardwarePreservesCordon verifies that DiscoverHardware only ever writes the
+// hardware keys under a node's config and leaves the lifecycle subtree (and thus drain state)
+// untouched, even across repeated calls.
+func TestDiscoverHardwarePreservesCordon(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+	executor := &fakeExecutor{output: "[]"}
+	netProber := &fakeProber{}
+
+	assert.Nil(t, DiscoverHardware("node1", etcdClient, executor, netProber))
+	assert.Nil(t, SetNodeDrain(etcdClient, "node1", true))
+
+	assert.Nil(t, DiscoverHardware("node1", etcdClient, executor, netProber))
+
+	drain, err := GetNodeDrain(etcdClient, "node1")
+	assert.Nil(t, err)
+	assert.True(t, drain)
+}
@@ -0,0 +1,121 @@
+package inventory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// locodeCountries is a small embedded table of ISO 3166-1 alpha-2 country codes accepted by
+// LocodeValidator. It is intentionally not exhaustive; extend it as new deployment regions come
+// online.
+var locodeCountries = map[string]bool{
+	"US": true, "CA": true, "MX": true,
+	"GB": true, "DE": true, "FR": true, "NL": true, "IE": true, "SE": true, "PL": true,
+	"JP": true, "SG": true, "IN": true, "AU": true, "KR": true,
+	"BR": true, "ZA": true,
+}
+
+// locodeCountryBounds gives a rough lat/long bounding box per country, used only to sanity
+// check a supplied lat/long attribute against its claimed LOCODE; it is not a precise geofence.
+var locodeCountryBounds = map[string][4]float64{
+	// {minLat, maxLat, minLong, maxLong}
+	"US": {24.0, 49.5, -125.0, -66.0},
+	"CA": {41.0, 83.0, -141.0, -52.0},
+	"GB": {49.8, 60.9, -8.6, 1.8},
+	"DE": {47.2, 55.1, 5.8, 15.1},
+	"FR": {41.3, 51.1, -5.2, 9.6},
+	"JP": {24.0, 45.6, 122.9, 153.9},
+	"IN": {6.7, 35.5, 68.0, 97.4},
+	"AU": {-43.7, -10.5, 112.9, 153.7},
+	"SG": {1.1, 1.5, 103.6, 104.1},
+}
+
+// LocodeValidator validates that an attribute value is a well-formed UN/LOCODE (e.g. "US NYC")
+// whose country portion is in locodeCountries. Whichever of AttrLocode/AttrCoordinates is set
+// second is additionally checked against the other one via ValidateCoordinates.
+type LocodeValidator struct{}
+
+// Validate implements AttributeValidator. It validates AttrLocode and AttrCoordinates; any other
+// key is accepted unconditionally, since LocodeValidator is meant to be composed with other
+// validators rather than used as the sole validator for a node's attributes.
+func (v LocodeValidator) Validate(key, value string, existing map[string]string) error {
+	switch key {
+	case AttrLocode:
+		if _, _, err := ParseLocode(value); err != nil {
+			return err
+		}
+		if coords := existing[AttrCoordinates]; coords != "" {
+			return ValidateCoordinates(value, coords)
+		}
+		return nil
+	case AttrCoordinates:
+		locode := existing[AttrLocode]
+		if locode == "" {
+			// nothing to cross-check against yet; it'll be checked once AttrLocode is set
+			return nil
+		}
+		return ValidateCoordinates(locode, value)
+	default:
+		return nil
+	}
+}
+
+// ParseLocode parses a UN/LOCODE of the form "CC LLL" (a two-letter ISO 3166-1 country code,
+// a space, and a three-letter location code) and returns its country and location parts. It
+// rejects countries not present in the embedded locodeCountries table.
+func ParseLocode(locode string) (country, location string, err error) {
+	fields := strings.Fields(locode)
+	if len(fields) != 2 {
+		return "", "", fmt.Errorf("locode %q must be a country code and location code separated by a space", locode)
+	}
+
+	country, location = strings.ToUpper(fields[0]), strings.ToUpper(fields[1])
+	if len(country) != 2 {
+		return "", "", fmt.Errorf("locode country %q must be exactly 2 letters", country)
+	}
+	if len(location) != 3 {
+		return "", "", fmt.Errorf("locode location %q must be exactly 3 letters", location)
+	}
+	if !locodeCountries[country] {
+		return "", "", fmt.Errorf("locode country %q is not in the known country table", country)
+	}
+
+	return country, location, nil
+}
+
+// ValidateCoordinates checks that a "lat,long" string falls within the rough bounding box for
+// locode's country. If no bounding box is known for the country, the coordinates are accepted
+// unchecked.
+func ValidateCoordinates(locode, latLong string) error {
+	country, _, err := ParseLocode(locode)
+	if err != nil {
+		return err
+	}
+
+	bounds, ok := locodeCountryBounds[country]
+	if !ok {
+		return nil
+	}
+
+	parts := strings.Split(latLong, ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("coordinates %q must be \"lat,long\"", latLong)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return fmt.Errorf("invalid latitude %q: %v", parts[0], err)
+	}
+	long, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return fmt.Errorf("invalid longitude %q: %v", parts[1], err)
+	}
+
+	minLat, maxLat, minLong, maxLong := bounds[0], bounds[1], bounds[2], bounds[3]
+	if lat < minLat || lat > maxLat || long < minLong || long > maxLong {
+		return fmt.Errorf("coordinates %s are outside the expected bounding box for %s", latLong, country)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocode(t *testing.T) {
+	country, location, err := ParseLocode("us nyc")
+	assert.Nil(t, err)
+	assert.Equal(t, "US", country)
+	assert.Equal(t, "NYC", location)
+
+	_, _, err = ParseLocode("USNYC")
+	assert.NotNil(t, err)
+
+	_, _, err = ParseLocode("ZZ NYC")
+	assert.NotNil(t, err, "unknown country should be rejected")
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	assert.Nil(t, ValidateCoordinates("US NYC", "40.7,-74.0"))
+	assert.NotNil(t, ValidateCoordinates("US NYC", "51.5,-0.1"), "london coordinates shouldn't validate against a US locode")
+	assert.Nil(t, ValidateCoordinates("SG SIN", "1.3,103.8"))
+}
@@ -0,0 +1,158 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"github.com/quantum/castle/pkg/proc"
+	"github.com/quantum/castle/pkg/util"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// dmiMemoryDevice is the subset of a `dmidecode --json -t memory` memory device entry this
+// package cares about. An empty-slot DIMM is reported with Size "No Module Installed".
+type dmiMemoryDevice struct {
+	Locator      string `json:"locator"`
+	Size         string `json:"size"`
+	Speed        string `json:"speed"`
+	Manufacturer string `json:"manufacturer"`
+}
+
+// discoverMemoryConfig probes the local node's installed memory via dmidecode and persists it
+// under nodeConfigKey/mem: a flat totalsize (kept for backwards compatibility) plus one entry
+// per populated DIMM slot under mem/dimms.
+func discoverMemoryConfig(nodeConfigKey string, etcdClient etcd.KeysAPI, executor proc.Executor) error {
+	mem, err := probeMemory(executor)
+	if err != nil {
+		return err
+	}
+
+	memKey := path.Join(nodeConfigKey, MemoryKey)
+	if _, err := etcdClient.Set(ctx.Background(), path.Join(memKey, MemoryTotalSizeKey), strconv.FormatUint(mem.TotalSize, 10), nil); err != nil {
+		return err
+	}
+
+	for _, dimm := range mem.Dimms {
+		dimmKey := path.Join(memKey, MemoryDimmsKey, dimm.Slot)
+		props := map[string]string{
+			DimmSizeBytesKey:    strconv.FormatUint(dimm.SizeBytes, 10),
+			DimmSpeedMHzKey:     strconv.FormatUint(uint64(dimm.SpeedMHz), 10),
+			DimmManufacturerKey: dimm.Manufacturer,
+		}
+
+		for propKey, propValue := range props {
+			if _, err := etcdClient.Set(ctx.Background(), path.Join(dimmKey, propKey), propValue, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// probeMemory runs dmidecode against the local node and unmarshals its JSON output into a
+// MemoryConfig.
+func probeMemory(executor proc.Executor) (MemoryConfig, error) {
+	out, err := executor.ExecuteCommandPipeline("dmidecode --json -t memory", nil)
+	if err != nil {
+		return MemoryConfig{}, err
+	}
+
+	var devices []dmiMemoryDevice
+	if err := json.Unmarshal([]byte(out), &devices); err != nil {
+		return MemoryConfig{}, fmt.Errorf("failed to parse dmidecode memory output: %v", err)
+	}
+
+	mem := MemoryConfig{}
+	for _, d := range devices {
+		sizeBytes, ok := parseDmiSizeBytes(d.Size)
+		if !ok {
+			// empty slot, e.g. "No Module Installed"
+			continue
+		}
+
+		mem.Dimms = append(mem.Dimms, DimmConfig{
+			Slot:         d.Locator,
+			SizeBytes:    sizeBytes,
+			SpeedMHz:     parseDmiSpeedMHz(d.Speed),
+			Manufacturer: d.Manufacturer,
+		})
+		mem.TotalSize += sizeBytes
+	}
+
+	return mem, nil
+}
+
+// parseDmiSizeBytes parses a dmidecode size string like "8192 MB" or "16 GB" into bytes. It
+// returns ok=false for unpopulated slots.
+func parseDmiSizeBytes(size string) (bytes uint64, ok bool) {
+	fields := strings.Fields(size)
+	if len(fields) != 2 {
+		return 0, false
+	}
+
+	amount, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	switch strings.ToUpper(fields[1]) {
+	case "GB":
+		return amount * 1024 * 1024 * 1024, true
+	case "MB":
+		return amount * 1024 * 1024, true
+	default:
+		return 0, false
+	}
+}
+
+// parseDmiSpeedMHz parses a dmidecode speed string like "2666 MT/s" into a plain MHz value.
+func parseDmiSpeedMHz(speed string) uint {
+	fields := strings.Fields(speed)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	mhz, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return uint(mhz)
+}
+
+// loadDimmsConfig parses the dimms subtree of a node's mem config into the MemoryConfig.
+func loadDimmsConfig(mem *MemoryConfig, dimmsRootNode *etcd.Node) error {
+	for _, dimmInfo := range dimmsRootNode.Nodes {
+		dimm := DimmConfig{Slot: util.GetLeafKeyPath(dimmInfo.Key)}
+
+		for _, prop := range dimmInfo.Nodes {
+			switch util.GetLeafKeyPath(prop.Key) {
+			case DimmSizeBytesKey:
+				size, err := strconv.ParseUint(prop.Value, 10, 64)
+				if err != nil {
+					return err
+				}
+				dimm.SizeBytes = size
+			case DimmSpeedMHzKey:
+				speed, err := strconv.ParseUint(prop.Value, 10, 32)
+				if err != nil {
+					return err
+				}
+				dimm.SpeedMHz = uint(speed)
+			case DimmManufacturerKey:
+				dimm.Manufacturer = prop.Value
+			}
+		}
+
+		mem.Dimms = append(mem.Dimms, dimm)
+	}
+
+	return nil
+}
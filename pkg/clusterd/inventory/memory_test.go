@@ -0,0 +1,22 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeMemory(t *testing.T) {
+	executor := &fakeExecutor{output: `[
+		{"locator": "DIMM_A1", "size": "16 GB", "speed": "2666 MT/s", "manufacturer": "Samsung"},
+		{"locator": "DIMM_A2", "size": "No Module Installed", "speed": "Unknown", "manufacturer": ""}
+	]`}
+
+	mem, err := probeMemory(executor)
+	assert.Nil(t, err)
+	assert.Len(t, mem.Dimms, 1)
+	assert.Equal(t, "DIMM_A1", mem.Dimms[0].Slot)
+	assert.Equal(t, uint64(16*1024*1024*1024), mem.Dimms[0].SizeBytes)
+	assert.Equal(t, uint(2666), mem.Dimms[0].SpeedMHz)
+	assert.Equal(t, mem.TotalSize, mem.Dimms[0].SizeBytes)
+}
@@ -0,0 +1,281 @@
+//go:build linux
+// +build linux
+
+package net
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// netlinkProber enumerates NICs by talking to the kernel directly over netlink
+// (RTM_GETLINK / RTM_GETADDR) and reading negotiated link speed via an ethtool ioctl, with no
+// subprocesses spawned.
+type netlinkProber struct{}
+
+func newNetlinkProber() NetworkProber {
+	return &netlinkProber{}
+}
+
+func (p *netlinkProber) Probe() ([]NIC, error) {
+	links, err := rtmGetLink()
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := rtmGetAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	nics := make([]NIC, 0, len(links))
+	for _, link := range links {
+		nic := NIC{
+			Name:      link.name,
+			MAC:       link.mac,
+			MTU:       link.mtu,
+			OperState: operStateName(link.operState),
+		}
+
+		if addr, ok := addrs[link.index]; ok {
+			nic.IPv4Address = addr.ipv4
+			nic.IPv6Address = addr.ipv6
+		}
+
+		if speed, err := ethtoolSpeed(link.name); err == nil {
+			nic.Speed = speed
+		}
+
+		nics = append(nics, nic)
+	}
+
+	return nics, nil
+}
+
+type netlinkLink struct {
+	index     int32
+	name      string
+	mac       string
+	mtu       int
+	operState uint8
+}
+
+// rtmGetLink dumps RTM_GETLINK over a netlink route socket and decodes each RTM_NEWLINK
+// message's attributes (IFLA_IFNAME, IFLA_ADDRESS, IFLA_MTU, IFLA_OPERSTATE) into netlinkLinks.
+func rtmGetLink() ([]netlinkLink, error) {
+	data, err := unix.NetlinkRIB(unix.RTM_GETLINK, unix.AF_UNSPEC)
+	if err != nil {
+		return nil, fmt.Errorf("RTM_GETLINK dump failed: %v", err)
+	}
+
+	msgs, err := unix.ParseNetlinkMessage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []netlinkLink
+	for i := range msgs {
+		msg := msgs[i]
+		if msg.Header.Type != unix.RTM_NEWLINK {
+			continue
+		}
+		if len(msg.Data) < unix.SizeofIfInfomsg {
+			continue
+		}
+
+		ifim := (*unix.IfInfomsg)(unsafe.Pointer(&msg.Data[0]))
+		link := netlinkLink{index: ifim.Index}
+
+		attrs, err := unix.ParseNetlinkRouteAttr(&msg)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case unix.IFLA_IFNAME:
+				link.name = strings.TrimRight(string(attr.Value), "\x00")
+			case unix.IFLA_ADDRESS:
+				link.mac = net.HardwareAddr(attr.Value).String()
+			case unix.IFLA_MTU:
+				if len(attr.Value) >= 4 {
+					link.mtu = int(binary.LittleEndian.Uint32(attr.Value))
+				}
+			case unix.IFLA_OPERSTATE:
+				// the kernel's RFC2863 carrier state (e.g. a link can be admin-up with no
+				// cable plugged in and still report "down" here), not net.FlagUp.
+				if len(attr.Value) > 0 {
+					link.operState = attr.Value[0]
+				}
+			}
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+type netlinkAddr struct {
+	ipv4 string
+	ipv6 string
+}
+
+// rtmGetAddr dumps RTM_GETADDR and returns the first IPv4/IPv6 address seen for each interface
+// index. IFA_LOCAL is preferred over IFA_ADDRESS since on point-to-point links IFA_ADDRESS is
+// the peer's address, not this interface's.
+func rtmGetAddr() (map[int32]*netlinkAddr, error) {
+	data, err := unix.NetlinkRIB(unix.RTM_GETADDR, unix.AF_UNSPEC)
+	if err != nil {
+		return nil, fmt.Errorf("RTM_GETADDR dump failed: %v", err)
+	}
+
+	msgs, err := unix.ParseNetlinkMessage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[int32]*netlinkAddr{}
+	for i := range msgs {
+		msg := msgs[i]
+		if msg.Header.Type != unix.RTM_NEWADDR {
+			continue
+		}
+		if len(msg.Data) < unix.SizeofIfAddrmsg {
+			continue
+		}
+
+		ifam := (*unix.IfAddrmsg)(unsafe.Pointer(&msg.Data[0]))
+		index := int32(ifam.Index)
+
+		attrs, err := unix.ParseNetlinkRouteAttr(&msg)
+		if err != nil {
+			return nil, err
+		}
+
+		var addrBytes []byte
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case unix.IFA_LOCAL:
+				addrBytes = attr.Value
+			case unix.IFA_ADDRESS:
+				if addrBytes == nil {
+					addrBytes = attr.Value
+				}
+			}
+		}
+		if addrBytes == nil {
+			continue
+		}
+
+		entry, ok := result[index]
+		if !ok {
+			entry = &netlinkAddr{}
+			result[index] = entry
+		}
+
+		switch ifam.Family {
+		case unix.AF_INET:
+			if entry.ipv4 == "" {
+				entry.ipv4 = net.IP(addrBytes).String()
+			}
+		case unix.AF_INET6:
+			if entry.ipv6 == "" {
+				entry.ipv6 = net.IP(addrBytes).String()
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// operStateName maps a kernel IFLA_OPERSTATE value (RFC2863 IF_OPER_*) to its canonical name.
+func operStateName(state uint8) string {
+	switch state {
+	case 0:
+		return "unknown"
+	case 1:
+		return "notpresent"
+	case 2:
+		return "down"
+	case 3:
+		return "lowerlayerdown"
+	case 4:
+		return "testing"
+	case 5:
+		return "dormant"
+	case 6:
+		return "up"
+	default:
+		return "unknown"
+	}
+}
+
+// ethtoolCmd mirrors struct ethtool_cmd from linux/ethtool.h field-for-field (44 bytes on every
+// arch, since every field lines up on its own natural alignment boundary). Getting this layout
+// wrong is not just cosmetic: the kernel's copy_to_user for ETHTOOL_GSET writes a full
+// sizeof(struct ethtool_cmd) into whatever ifr.data points at, so an undersized struct here gets
+// the tail of the kernel's response scribbled into adjacent heap memory.
+type ethtoolCmd struct {
+	cmd           uint32
+	supported     uint32
+	advertising   uint32
+	speed         uint16
+	duplex        uint8
+	port          uint8
+	phyAddress    uint8
+	transceiver   uint8
+	autoneg       uint8
+	mdioSupport   uint8
+	maxtxpkt      uint32
+	maxrxpkt      uint32
+	speedHi       uint16
+	ethTpMdix     uint8
+	ethTpMdixCtrl uint8
+	lpAdvertising uint32
+	reserved      [2]uint32
+}
+
+// ifreq mirrors struct ifreq from linux/if.h for the ifr_name/ifr_data layout used by
+// SIOCETHTOOL: a 16-byte interface name followed by the ifr_ifru union, whose first member we
+// care about (ifr_data) is a plain pointer. The trailing padding brings the struct up to the
+// union's real size (sizeof(struct ifmap)) so it matches the kernel's 40-byte struct ifreq.
+type ifreq struct {
+	name [unix.IFNAMSIZ]byte
+	data unsafe.Pointer
+	_    [16]byte
+}
+
+// ethtoolSpeed reads the negotiated link speed (in Mb/s) for a NIC via the ETHTOOL_GSET ioctl,
+// the same call the `ethtool` binary itself uses under the hood.
+func ethtoolSpeed(name string) (uint64, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer unix.Close(fd)
+
+	cmd := ethtoolCmd{cmd: unix.ETHTOOL_GSET}
+
+	var ifr ifreq
+	copy(ifr.name[:], name)
+	ifr.data = unsafe.Pointer(&cmd)
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.SIOCETHTOOL, uintptr(unsafe.Pointer(&ifr))); errno != 0 {
+		return 0, fmt.Errorf("ethtool ioctl failed for %s: %v", name, errno)
+	}
+
+	// mirrors the kernel's own ethtool_cmd_speed() helper: speed_hi holds the upper 16 bits for
+	// link speeds above 65535 Mb/s.
+	speed := uint64(cmd.speedHi)<<16 | uint64(cmd.speed)
+	if speed == 0 || speed == 0xffff {
+		return 0, fmt.Errorf("unknown link speed for %s", name)
+	}
+
+	return speed, nil
+}
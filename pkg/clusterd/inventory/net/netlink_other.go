@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package net
+
+// newNetlinkProber returns nil on platforms without netlink support, so NewProber falls back
+// to the shell-based prober.
+func newNetlinkProber() NetworkProber {
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package net collects the set of network adapters present on the local node.
+package net
+
+import "github.com/quantum/castle/pkg/proc"
+
+// NIC describes a single network interface as reported by a NetworkProber.
+type NIC struct {
+	Name        string
+	MAC         string
+	IPv4Address string
+	IPv6Address string
+	MTU         int
+	OperState   string
+	Speed       uint64 // negotiated link speed, in Mb/s
+}
+
+// NetworkProber enumerates the network interfaces on the local node. Implementations may talk
+// to the kernel directly (netlink) or fall back to shelling out to userspace tools.
+type NetworkProber interface {
+	Probe() ([]NIC, error)
+}
+
+// NewProber returns the best available NetworkProber for the current platform: a netlink-backed
+// collector where supported, falling back to a shell-based collector everywhere else.
+func NewProber(executor proc.Executor) NetworkProber {
+	if prober := newNetlinkProber(); prober != nil {
+		return prober
+	}
+
+	return newShellProber(executor)
+}
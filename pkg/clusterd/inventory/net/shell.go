@@ -0,0 +1,122 @@
+package net
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/quantum/castle/pkg/proc"
+)
+
+// shellProber collects NICs by shelling out to `ip` and `ethtool` and parsing their textual
+// output. It exists as a fallback for platforms where the netlink-backed prober is unavailable.
+type shellProber struct {
+	executor proc.Executor
+}
+
+func newShellProber(executor proc.Executor) NetworkProber {
+	return &shellProber{executor: executor}
+}
+
+func (p *shellProber) Probe() ([]NIC, error) {
+	out, err := p.executor.ExecuteCommandPipeline("ip -o addr show", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nicsByName := map[string]*NIC{}
+	var order []string
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		name := fields[1]
+		nic, ok := nicsByName[name]
+		if !ok {
+			nic = &NIC{Name: name}
+			nicsByName[name] = nic
+			order = append(order, name)
+		}
+
+		switch fields[2] {
+		case "inet":
+			nic.IPv4Address = strings.SplitN(fields[3], "/", 2)[0]
+		case "inet6":
+			nic.IPv6Address = strings.SplitN(fields[3], "/", 2)[0]
+		}
+	}
+
+	nics := make([]NIC, 0, len(order))
+	for _, name := range order {
+		nic := nicsByName[name]
+		nic.MTU, nic.OperState = p.linkDetails(name)
+		nic.MAC = p.macAddress(name)
+		nic.Speed = p.linkSpeed(name)
+		nics = append(nics, *nic)
+	}
+
+	return nics, nil
+}
+
+// linkDetails parses `ip -o link show <name>` output, e.g.:
+//
+//	2: eth0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc mq state UP mode DEFAULT group default qlen 1000
+//
+// The part after the flags is a run of space-separated "key value" pairs (no '=' involved), so
+// it's parsed by scanning adjacent field pairs rather than splitting on '='.
+func (p *shellProber) linkDetails(name string) (mtu int, operState string) {
+	out, err := p.executor.ExecuteCommandPipeline("ip -o link show "+name, nil)
+	if err != nil {
+		return 0, ""
+	}
+
+	fields := strings.Fields(out)
+	for i := 0; i+1 < len(fields); i++ {
+		switch fields[i] {
+		case "mtu":
+			mtu, _ = strconv.Atoi(fields[i+1])
+		case "state":
+			operState = strings.ToLower(fields[i+1])
+		}
+	}
+
+	return mtu, operState
+}
+
+func (p *shellProber) macAddress(name string) string {
+	out, err := p.executor.ExecuteCommandPipeline("cat /sys/class/net/"+name+"/address", nil)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(out)
+}
+
+// linkSpeed parses the "Speed: 1000Mb/s" line out of `ethtool <name>` output, which is a block
+// of "Key: value" lines (one per line), not the space-separated "key value" format ip link uses.
+func (p *shellProber) linkSpeed(name string) uint64 {
+	out, err := p.executor.ExecuteCommandPipeline("ethtool "+name, nil)
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) != "Speed" {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		value = strings.TrimSuffix(value, "Mb/s")
+		speed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		return speed
+	}
+
+	return 0
+}
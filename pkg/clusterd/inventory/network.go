@@ -0,0 +1,41 @@
+package inventory
+
+import (
+	"path"
+	"strconv"
+
+	ctx "golang.org/x/net/context"
+
+	netprobe "github.com/quantum/castle/pkg/clusterd/inventory/net"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+// discoverNetworkConfig probes the local node's network adapters via prober and persists them
+// under nodeConfigKey/net.
+func discoverNetworkConfig(nodeConfigKey string, etcdClient etcd.KeysAPI, prober netprobe.NetworkProber) error {
+	nics, err := prober.Probe()
+	if err != nil {
+		return err
+	}
+
+	for _, nic := range nics {
+		nicKey := path.Join(nodeConfigKey, NetworkKey, nic.Name)
+		props := map[string]string{
+			NetworkIPv4AddressKey: nic.IPv4Address,
+			NetworkIPv6AddressKey: nic.IPv6Address,
+			NetworkSpeedKey:       strconv.FormatUint(nic.Speed, 10),
+			NetworkMACKey:         nic.MAC,
+			NetworkMTUKey:         strconv.Itoa(nic.MTU),
+			NetworkOperStateKey:   nic.OperState,
+		}
+
+		for propKey, propValue := range props {
+			if _, err := etcdClient.Set(ctx.Background(), path.Join(nicKey, propKey), propValue, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package inventory
+
+import (
+	"testing"
+
+	ctx "golang.org/x/net/context"
+
+	netprobe "github.com/quantum/castle/pkg/clusterd/inventory/net"
+	"github.com/stretchr/testify/assert"
+
+	etcd "github.com/coreos/etcd/client"
+)
+
+type fakeProber struct {
+	nics []netprobe.NIC
+	err  error
+}
+
+func (p *fakeProber) Probe() ([]netprobe.NIC, error) {
+	return p.nics, p.err
+}
+
+func TestDiscoverNetworkConfig(t *testing.T) {
+	etcdClient := newFakeKeysAPI()
+	prober := &fakeProber{nics: []netprobe.NIC{
+		{Name: "eth0", MAC: "aa:bb:cc:dd:ee:ff", IPv4Address: "10.0.0.5", MTU: 1500, OperState: "up", Speed: 10000},
+	}}
+
+	nodeConfigKey := GetNodeConfigKey("node1")
+	assert.Nil(t, discoverNetworkConfig(nodeConfigKey, etcdClient, prober))
+
+	nodeInfo, err := etcdClient.Get(ctx.Background(), nodeConfigKey, &etcd.GetOptions{Recursive: true})
+	assert.Nil(t, err)
+
+	nodeConfig := &NodeConfig{}
+	assert.Nil(t, loadHardwareConfig("node1", nodeConfig, nodeInfo))
+	assert.Len(t, nodeConfig.NetworkAdapters, 1)
+
+	nic := nodeConfig.NetworkAdapters[0]
+	assert.Equal(t, "aa:bb:cc:dd:ee:ff", nic.MAC)
+	assert.Equal(t, 1500, nic.MTU)
+	assert.Equal(t, "up", nic.OperState)
+	assert.Equal(t, uint64(10000), nic.Speed)
+}
@@ -10,6 +10,7 @@ import (
 
 	ctx "golang.org/x/net/context"
 
+	netprobe "github.com/quantum/castle/pkg/clusterd/inventory/net"
 	"github.com/quantum/castle/pkg/proc"
 	"github.com/quantum/castle/pkg/util"
 
@@ -24,13 +25,30 @@ const (
 	MemoryKey     = "mem"
 )
 
-func DiscoverHardware(nodeID string, etcdClient etcd.KeysAPI, executor proc.Executor) error {
+// DiscoverHardware probes and persists the hardware available on a node. It only ever writes
+// hardware keys (disks, cpu, mem, net); it never touches the lifecycle subtree, so a cordoned
+// or decommissioned node stays that way across repeated client-driven rediscovery.
+//
+// netProber is taken as a parameter rather than constructed internally so that callers (and
+// tests) can supply a prober of their choosing instead of always hitting the real netlink/ioctl
+// or shell-based collector. Production callers should pass netprobe.NewProber(executor).
+func DiscoverHardware(nodeID string, etcdClient etcd.KeysAPI, executor proc.Executor, netProber netprobe.NetworkProber) error {
 	nodeConfigKey := GetNodeConfigKey(nodeID)
 	if err := discoverDisks(nodeConfigKey, etcdClient, executor); err != nil {
 		return err
 	}
 
-	// TODO: discover more hardware properties
+	if err := discoverNetworkConfig(nodeConfigKey, etcdClient, netProber); err != nil {
+		return err
+	}
+
+	if err := discoverProcessorsConfig(nodeConfigKey, etcdClient, executor); err != nil {
+		return err
+	}
+
+	if err := discoverMemoryConfig(nodeConfigKey, etcdClient, executor); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -53,31 +71,13 @@ func loadNodeConfig(etcdClient etcd.KeysAPI) (map[string]*NodeConfig, error) {
 
 	nodesConfig := make(map[string]*NodeConfig)
 	for node := range nodes.Iter() {
-		nodeConfig := &NodeConfig{}
-
-		// get all the config information for the current node
-		configKey := GetNodeConfigKey(node)
-		nodeInfo, err := etcdClient.Get(ctx.Background(), configKey, &etcd.GetOptions{Recursive: true})
-		if err != nil {
-			if util.IsEtcdKeyNotFound(err) {
-				log.Printf("skipping node %s with no hardware discovered", node)
-				continue
-			}
-			log.Printf("failed to get hardware info from etcd for node %s, %v", node, err)
-		} else {
-			err = loadHardwareConfig(node, nodeConfig, nodeInfo)
-			if err != nil {
-				log.Printf("failed to parse hardware config for node %s, %v", node, err)
-				return nil, err
-			}
-		}
-
-		ipAddr, err := GetIpAddress(etcdClient, node)
+		nodeConfig, err := loadOneNodeConfig(etcdClient, node)
 		if err != nil {
-			log.Printf("failed to get IP address for node %s, %+v", node, err)
 			return nil, err
 		}
-		nodeConfig.IPAddress = ipAddr
+		if nodeConfig == nil {
+			continue
+		}
 
 		nodesConfig[node] = nodeConfig
 	}
@@ -85,6 +85,38 @@ func loadNodeConfig(etcdClient etcd.KeysAPI) (map[string]*NodeConfig, error) {
 	return nodesConfig, nil
 }
 
+// loadOneNodeConfig loads the full infrastructure configuration for a single node. It returns
+// a nil NodeConfig (with no error) if the node has no hardware discovered yet.
+func loadOneNodeConfig(etcdClient etcd.KeysAPI, node string) (*NodeConfig, error) {
+	nodeConfig := &NodeConfig{LifecycleState: NodeLifecycleReady}
+
+	// get all the config information for the current node
+	configKey := GetNodeConfigKey(node)
+	nodeInfo, err := etcdClient.Get(ctx.Background(), configKey, &etcd.GetOptions{Recursive: true})
+	if err != nil {
+		if util.IsEtcdKeyNotFound(err) {
+			log.Printf("skipping node %s with no hardware discovered", node)
+			return nil, nil
+		}
+		log.Printf("failed to get hardware info from etcd for node %s, %v", node, err)
+		return nil, err
+	}
+
+	if err := loadHardwareConfig(node, nodeConfig, nodeInfo); err != nil {
+		log.Printf("failed to parse hardware config for node %s, %v", node, err)
+		return nil, err
+	}
+
+	ipAddr, err := GetIpAddress(etcdClient, node)
+	if err != nil {
+		log.Printf("failed to get IP address for node %s, %+v", node, err)
+		return nil, err
+	}
+	nodeConfig.IPAddress = ipAddr
+
+	return nodeConfig, nil
+}
+
 // Get the IP address for a node
 func GetIpAddress(etcdClient etcd.KeysAPI, nodeId string) (string, error) {
 	key := path.Join(GetNodeConfigKey(nodeId), IpAddressKey)
@@ -142,6 +174,18 @@ func loadHardwareConfig(nodeId string, nodeConfig *NodeConfig, nodeInfo *etcd.Re
 				log.Printf("failed to load IP address config for node %s, %v", nodeId, err)
 				return err
 			}
+		case LifecycleKey:
+			err := loadLifecycleConfig(nodeConfig, nodeConfigRoot)
+			if err != nil {
+				log.Printf("failed to load lifecycle config for node %s, %v", nodeId, err)
+				return err
+			}
+		case AttrsKey:
+			err := loadAttributesConfig(nodeConfig, nodeConfigRoot)
+			if err != nil {
+				log.Printf("failed to load attributes config for node %s, %v", nodeId, err)
+				return err
+			}
 		default:
 			log.Printf("unexpected hardware component: %s, skipping...", nodeConfigRoot)
 		}
@@ -230,6 +274,18 @@ func loadProcessorsConfig(nodeConfig *NodeConfig, procsRootNode *etcd.Node) erro
 				} else {
 					proc.Bits = uint(numBits)
 				}
+			case ProcModelNameKey:
+				proc.ModelName = procProperty.Value
+			case ProcFlagsKey:
+				if procProperty.Value != "" {
+					proc.Flags = strings.Split(procProperty.Value, " ")
+				}
+			case ProcCacheKBKey:
+				if cacheKB, err := strconv.ParseUint(procProperty.Value, 10, 32); err != nil {
+					return err
+				} else {
+					proc.CacheKB = uint(cacheKB)
+				}
 			default:
 				log.Printf("unknown processor property key %s, skipping", procPropertyName)
 			}
@@ -252,6 +308,10 @@ func loadMemoryConfig(nodeConfig *NodeConfig, memoryRootNode *etcd.Node) error {
 			} else {
 				mem.TotalSize = size
 			}
+		case MemoryDimmsKey:
+			if err := loadDimmsConfig(&mem, memProperty); err != nil {
+				return err
+			}
 		default:
 			log.Printf("unknown memory property key %s, skipping", memPropertyName)
 		}
@@ -290,6 +350,18 @@ func loadNetworkConfig(nodeConfig *NodeConfig, networkRootNode *etcd.Node) error
 				} else {
 					net.Speed = speed
 				}
+			case NetworkMACKey:
+				net.MAC = netProperty.Value
+			case NetworkMTUKey:
+				if netProperty.Value == "" {
+					net.MTU = 0
+				} else if mtu, err := strconv.Atoi(netProperty.Value); err != nil {
+					return err
+				} else {
+					net.MTU = mtu
+				}
+			case NetworkOperStateKey:
+				net.OperState = netProperty.Value
 			default:
 				log.Printf("unknown network adapter property key %s, skipping", netPropertyName)
 			}
@@ -308,25 +380,3 @@ func loadIPAddressConfig(nodeConfig *NodeConfig, ipAddressNode *etcd.Node) error
 	nodeConfig.IPAddress = ipAddressNode.Value
 	return nil
 }
-
-// converts a raw key value pair string into a map of key value pairs
-// example raw string of `foo="0" bar="1" baz="biz"` is returned as:
-// map[string]string{"foo":"0", "bar":"1", "baz":"biz"}
-func parseKeyValuePairString(propsRaw string) map[string]string {
-	// first split the single raw string on spaces and initialize a map of
-	// a length equal to the number of pairs
-	props := strings.Split(propsRaw, " ")
-	propMap := make(map[string]string, len(props))
-
-	for _, kvpRaw := range props {
-		// split each individual key value pair on the equals sign
-		kvp := strings.Split(kvpRaw, "=")
-		if len(kvp) == 2 {
-			// first element is the final key, second element is the final value
-			// (don't forget to remove surrounding quotes from the value)
-			propMap[kvp[0]] = strings.Replace(kvp[1], `"`, "", -1)
-		}
-	}
-
-	return propMap
-}
\ No newline at end of file
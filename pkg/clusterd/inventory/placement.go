@@ -0,0 +1,93 @@
+package inventory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// attrPredicate is a single "key=value" or "key!=value" clause of a PlacementSelector.
+type attrPredicate struct {
+	key     string
+	negated bool
+	value   string
+}
+
+func (p attrPredicate) matches(attrs map[string]string) bool {
+	equal := attrs[p.key] == p.value
+	if p.negated {
+		return !equal
+	}
+	return equal
+}
+
+// PlacementSelector filters nodes by their attributes (see SetNodeAttribute), so callers can
+// spread replicas across failure domains, e.g. "region=us-east AND rack!=r1".
+type PlacementSelector struct {
+	predicates []attrPredicate
+}
+
+// ParsePlacementSelector parses a selector expression of one or more "key=value" or
+// "key!=value" clauses joined by "AND".
+func ParsePlacementSelector(expr string) (*PlacementSelector, error) {
+	clauses := strings.Split(expr, "AND")
+	predicates := make([]attrPredicate, 0, len(clauses))
+
+	for _, clause := range clauses {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			return nil, fmt.Errorf("empty clause in placement selector %q", expr)
+		}
+
+		predicate, err := parsePredicate(clause)
+		if err != nil {
+			return nil, err
+		}
+
+		predicates = append(predicates, predicate)
+	}
+
+	return &PlacementSelector{predicates: predicates}, nil
+}
+
+func parsePredicate(clause string) (attrPredicate, error) {
+	if idx := strings.Index(clause, "!="); idx >= 0 {
+		return attrPredicate{
+			key:     strings.TrimSpace(clause[:idx]),
+			negated: true,
+			value:   strings.TrimSpace(clause[idx+2:]),
+		}, nil
+	}
+
+	if idx := strings.Index(clause, "="); idx >= 0 {
+		return attrPredicate{
+			key:   strings.TrimSpace(clause[:idx]),
+			value: strings.TrimSpace(clause[idx+1:]),
+		}, nil
+	}
+
+	return attrPredicate{}, fmt.Errorf("clause %q is not of the form key=value or key!=value", clause)
+}
+
+// Matches returns true if nodeConfig's attributes satisfy every clause of the selector.
+func (s *PlacementSelector) Matches(nodeConfig *NodeConfig) bool {
+	for _, predicate := range s.predicates {
+		if !predicate.matches(nodeConfig.Attributes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns the subset of nodes (as from loadNodeConfig) whose attributes satisfy the
+// selector.
+func (s *PlacementSelector) Filter(nodes map[string]*NodeConfig) map[string]*NodeConfig {
+	filtered := make(map[string]*NodeConfig)
+	for nodeID, nodeConfig := range nodes {
+		if s.Matches(nodeConfig) {
+			filtered[nodeID] = nodeConfig
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,28 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlacementSelectorFilter(t *testing.T) {
+	nodes := map[string]*NodeConfig{
+		"node1": {Attributes: map[string]string{AttrRegion: "us-east", AttrRack: "r1"}},
+		"node2": {Attributes: map[string]string{AttrRegion: "us-east", AttrRack: "r2"}},
+		"node3": {Attributes: map[string]string{AttrRegion: "us-west", AttrRack: "r1"}},
+	}
+
+	selector, err := ParsePlacementSelector("region=us-east AND rack!=r1")
+	assert.Nil(t, err)
+
+	filtered := selector.Filter(nodes)
+	assert.Len(t, filtered, 1)
+	_, ok := filtered["node2"]
+	assert.True(t, ok)
+}
+
+func TestParsePlacementSelectorInvalid(t *testing.T) {
+	_, err := ParsePlacementSelector("region us-east")
+	assert.NotNil(t, err)
+}
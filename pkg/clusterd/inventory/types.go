@@ -0,0 +1,64 @@
+package inventory
+
+// NodeConfig is the full set of hardware and lifecycle information known about a single node.
+type NodeConfig struct {
+	IPAddress       string
+	Disks           []DiskConfig
+	Processors      []ProcessorConfig
+	Memory          MemoryConfig
+	NetworkAdapters []NetworkConfig
+	LifecycleState  NodeLifecycleState
+	Attributes      map[string]string
+}
+
+// DiskConfig describes a single disk discovered on a node.
+type DiskConfig struct {
+	Name       string
+	Size       uint64
+	UUID       string
+	Rotational bool
+	Model      string
+	Vendor     string
+	Serial     string
+	WWN        string
+	Transport  string // e.g. "sata", "nvme", "sas"
+}
+
+// ProcessorConfig describes a single logical processor discovered on a node.
+type ProcessorConfig struct {
+	ID         uint
+	PhysicalID uint
+	Siblings   uint
+	CoreID     uint
+	NumCores   uint
+	Speed      float64 // MHz
+	Bits       uint    // 32 or 64
+	ModelName  string
+	Flags      []string
+	CacheKB    uint
+}
+
+// DimmConfig describes a single memory DIMM discovered on a node.
+type DimmConfig struct {
+	Slot         string
+	SizeBytes    uint64
+	SpeedMHz     uint
+	Manufacturer string
+}
+
+// MemoryConfig describes the memory discovered on a node.
+type MemoryConfig struct {
+	TotalSize uint64 // in bytes
+	Dimms     []DimmConfig
+}
+
+// NetworkConfig describes a single network adapter discovered on a node.
+type NetworkConfig struct {
+	Name        string
+	IPv4Address string
+	IPv6Address string
+	Speed       uint64 // in Mb/s (megabits per second)
+	MAC         string
+	MTU         int
+	OperState   string // e.g. "up", "down"
+}